@@ -0,0 +1,30 @@
+// Package metrics registers the Prometheus collectors faasd exposes on
+// its metrics endpoint, alongside whatever the provider and gateway
+// already publish.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// SecretOpsTotal counts every secret operation faasd serves, labelled by
+// operation and result, so operators can see rejected or failing calls
+// without grepping logs. namespace is deliberately not a label: it comes
+// from the request before authorization, so an unauthenticated caller
+// could otherwise mint unbounded series by varying it.
+var SecretOpsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "faasd_secret_ops_total",
+	Help: "Total number of secret operations, by operation and result",
+}, []string{"op", "result"})
+
+// SecretOpDuration records how long each secret operation takes.
+var SecretOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "faasd_secret_op_duration_seconds",
+	Help:    "Duration of secret operations in seconds, by operation",
+	Buckets: prometheus.DefBuckets,
+}, []string{"op"})
+
+// RegisterSecretMetrics adds the secret-operation collectors to
+// registry. Call this once, alongside faasd's other metrics
+// registration, before serving the metrics endpoint.
+func RegisterSecretMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(SecretOpsTotal, SecretOpDuration)
+}
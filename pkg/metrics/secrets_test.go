@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestSecretOpsTotalHasNoNamespaceLabel locks in that SecretOpsTotal is
+// labelled only by op and result. namespace must never be added back as
+// a label: it is taken from the request before authorization, so an
+// unauthenticated caller could otherwise mint unbounded series by
+// varying it.
+func TestSecretOpsTotalHasNoNamespaceLabel(t *testing.T) {
+	SecretOpsTotal.Reset()
+	SecretOpsTotal.WithLabelValues("list", "success").Inc()
+
+	if got := testutil.ToFloat64(SecretOpsTotal.WithLabelValues("list", "success")); got != 1 {
+		t.Fatalf("got %v, want 1", got)
+	}
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mintTokenRequest is the body accepted by MakeAdminTokenHandler.
+type mintTokenRequest struct {
+	Subject string `json:"subject"`
+	TTL     string `json:"ttl"`
+}
+
+type mintTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// checkAdminSecret compares the bearer token on r against adminSecret in
+// constant time, so minting a namespace-scoped token first requires
+// presenting the pre-shared admin credential faasd was started with.
+// Without this, anyone who can reach the endpoint could mint a token for
+// any subject, including one a Policy rule grants broad access to.
+func checkAdminSecret(r *http.Request, adminSecret []byte) error {
+	if len(adminSecret) == 0 {
+		return fmt.Errorf("admin token minting is disabled: no admin secret configured")
+	}
+
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return fmt.Errorf("missing admin bearer credential")
+	}
+
+	presented := strings.TrimPrefix(header, prefix)
+	if subtle.ConstantTimeCompare([]byte(presented), adminSecret) != 1 {
+		return fmt.Errorf("invalid admin credential")
+	}
+
+	return nil
+}
+
+// MakeAdminTokenHandler returns a handler that mints namespace-scoped
+// JWTs for the given subject, signed with signingSecret. The caller must
+// present adminSecret as a bearer credential before a token is minted;
+// policy assignment itself still comes from the policy file loaded into
+// policy, and this endpoint only issues credentials for subjects that
+// already appear as rule subjects there.
+func MakeAdminTokenHandler(signingSecret, adminSecret []byte, policy *PolicyStore) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := checkAdminSecret(r, adminSecret); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		req := mintTokenRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.Subject == "" {
+			http.Error(w, "subject is required", http.StatusBadRequest)
+			return
+		}
+
+		if !policy.HasSubject(req.Subject) {
+			http.Error(w, fmt.Sprintf("subject %q does not appear in any policy rule", req.Subject), http.StatusForbidden)
+			return
+		}
+
+		ttl := 24 * time.Hour
+		if req.TTL != "" {
+			parsed, err := time.ParseDuration(req.TTL)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid ttl: %s", err), http.StatusBadRequest)
+				return
+			}
+			ttl = parsed
+		}
+
+		claims := jwt.MapClaims{
+			"sub": req.Subject,
+			"exp": time.Now().Add(ttl).Unix(),
+			"iat": time.Now().Unix(),
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signed, err := token.SignedString(signingSecret)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to sign token: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		bytesOut, _ := json.Marshal(mintTokenResponse{Token: signed})
+		w.Write(bytesOut)
+	}
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestPolicyStore(t *testing.T, yaml string) *PolicyStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatalf("write policy file: %s", err)
+	}
+	store, err := NewPolicyStore(path)
+	if err != nil {
+		t.Fatalf("NewPolicyStore: %s", err)
+	}
+	return store
+}
+
+func mintTokenRequestHTTP(subject string) *http.Request {
+	body, _ := json.Marshal(mintTokenRequest{Subject: subject})
+	r := httptest.NewRequest(http.MethodPost, "/system/auth/token", bytes.NewReader(body))
+	r.Header.Set("Authorization", "Bearer admin-secret")
+	return r
+}
+
+func TestMakeAdminTokenHandlerRejectsSubjectNotInPolicy(t *testing.T) {
+	policy := newTestPolicyStore(t, `
+rules:
+  - subject: team-a-bot
+    namespaces: ["team-a"]
+    actions: ["read"]
+`)
+
+	handler := MakeAdminTokenHandler([]byte("signing-secret"), []byte("admin-secret"), policy)
+
+	w := httptest.NewRecorder()
+	handler(w, mintTokenRequestHTTP("someone-else"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestMakeAdminTokenHandlerMintsTokenForPolicySubject(t *testing.T) {
+	policy := newTestPolicyStore(t, `
+rules:
+  - subject: team-a-bot
+    namespaces: ["team-a"]
+    actions: ["read"]
+`)
+
+	handler := MakeAdminTokenHandler([]byte("signing-secret"), []byte("admin-secret"), policy)
+
+	w := httptest.NewRecorder()
+	handler(w, mintTokenRequestHTTP("team-a-bot"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d, body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp mintTokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %s", err)
+	}
+	if resp.Token == "" {
+		t.Fatalf("expected a non-empty token")
+	}
+}
+
+func TestMakeAdminTokenHandlerRejectsWithoutAdminSecret(t *testing.T) {
+	policy := newTestPolicyStore(t, `
+rules:
+  - subject: team-a-bot
+    namespaces: ["team-a"]
+    actions: ["read"]
+`)
+
+	handler := MakeAdminTokenHandler([]byte("signing-secret"), []byte("admin-secret"), policy)
+
+	body, _ := json.Marshal(mintTokenRequest{Subject: "team-a-bot"})
+	r := httptest.NewRequest(http.MethodPost, "/system/auth/token", bytes.NewReader(body))
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Authorizer is the entry point handlers use to enforce multi-tenant
+// access control: it resolves the caller's identity from the request and
+// checks it against the loaded Policy for the given namespace and action.
+type Authorizer struct {
+	validator TokenValidator
+	policy    *PolicyStore
+}
+
+// NewAuthorizer builds an Authorizer from a TokenValidator and a
+// PolicyStore.
+func NewAuthorizer(validator TokenValidator, policy *PolicyStore) *Authorizer {
+	return &Authorizer{validator: validator, policy: policy}
+}
+
+// Actions recognised by policy rules.
+const (
+	ActionRead  = "read"
+	ActionWrite = "write"
+)
+
+// Authorize validates the bearer token on r and checks that its subject
+// may perform action against namespace. It returns the resolved subject
+// so callers can attribute the action (e.g. in an audit log).
+func (a *Authorizer) Authorize(r *http.Request, namespace, action string) (string, error) {
+	if a == nil {
+		return "", fmt.Errorf("no authorizer configured")
+	}
+
+	subject, err := SubjectFromRequest(r, a.validator)
+	if err != nil {
+		return "", err
+	}
+
+	if !a.policy.Allows(subject, namespace, action) {
+		return subject, errForbidden{subject: subject, namespace: namespace, action: action}
+	}
+
+	return subject, nil
+}
+
+type errForbidden struct {
+	subject   string
+	namespace string
+	action    string
+}
+
+func (e errForbidden) Error() string {
+	return "subject " + e.subject + " is not permitted to " + e.action + " in namespace " + e.namespace
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPolicyStoreHasSubject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(`
+rules:
+  - subject: team-a-bot
+    namespaces: ["team-a"]
+    actions: ["read", "write"]
+  - subject: "*"
+    namespaces: ["public"]
+    actions: ["read"]
+`), 0600); err != nil {
+		t.Fatalf("write policy file: %s", err)
+	}
+
+	store, err := NewPolicyStore(path)
+	if err != nil {
+		t.Fatalf("NewPolicyStore: %s", err)
+	}
+
+	if !store.HasSubject("team-a-bot") {
+		t.Fatalf("expected team-a-bot to be a known rule subject")
+	}
+	if store.HasSubject("unknown-subject") {
+		t.Fatalf("did not expect unknown-subject to be a known rule subject")
+	}
+	if store.HasSubject("*") {
+		t.Fatalf("a wildcard rule subject should not itself be mintable")
+	}
+}
+
+// TestPolicyStoreReloadsAfterAtomicReplace exercises the same update
+// pattern config-management tools and editors use: write the new
+// content to a temp file in the same directory, then rename it over the
+// target. That replaces the watched inode, which a watch on the file
+// itself would silently stop seeing events for.
+func TestPolicyStoreReloadsAfterAtomicReplace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte(`
+rules:
+  - subject: team-a-bot
+    namespaces: ["team-a"]
+    actions: ["read"]
+`), 0600); err != nil {
+		t.Fatalf("write policy file: %s", err)
+	}
+
+	store, err := NewPolicyStore(path)
+	if err != nil {
+		t.Fatalf("NewPolicyStore: %s", err)
+	}
+
+	if !store.HasSubject("team-a-bot") {
+		t.Fatalf("expected team-a-bot to be a known rule subject before replace")
+	}
+
+	tmpPath := filepath.Join(dir, "policy.yaml.tmp")
+	if err := os.WriteFile(tmpPath, []byte(`
+rules:
+  - subject: team-b-bot
+    namespaces: ["team-b"]
+    actions: ["read"]
+`), 0600); err != nil {
+		t.Fatalf("write replacement policy file: %s", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		t.Fatalf("rename replacement over policy file: %s", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if store.HasSubject("team-b-bot") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("policy was not reloaded after the file was replaced via rename")
+}
+
+func TestPolicyAllowsWildcardNamespace(t *testing.T) {
+	policy := Policy{Rules: []Rule{
+		{Subject: "team-a-bot", Namespaces: []string{"team-a/*"}, Actions: []string{"read"}},
+	}}
+
+	if !policy.allows("team-a-bot", "team-a/staging", "read") {
+		t.Fatalf("expected team-a/* to match team-a/staging")
+	}
+	if policy.allows("team-a-bot", "team-b/staging", "read") {
+		t.Fatalf("did not expect team-a/* to match team-b/staging")
+	}
+	if policy.allows("team-a-bot", "team-a/staging", "write") {
+		t.Fatalf("did not expect a read-only rule to permit write")
+	}
+}
+
+func TestPolicyAllowsWildcardSubjectAndAction(t *testing.T) {
+	policy := Policy{Rules: []Rule{
+		{Subject: "*", Namespaces: []string{"*"}, Actions: []string{"*"}},
+	}}
+
+	if !policy.allows("anyone", "any-namespace", "write") {
+		t.Fatalf("expected a fully wildcarded rule to allow any subject/namespace/action")
+	}
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenValidator turns a bearer token carried on an incoming request into
+// the subject it was issued to, so that subject can be checked against a
+// Policy. Two implementations are provided: StaticTokenValidator for a
+// flat file of pre-shared tokens, and JWTValidator for signed tokens
+// minted by MakeAdminTokenHandler.
+type TokenValidator interface {
+	Validate(token string) (subject string, err error)
+}
+
+// SubjectFromRequest extracts the bearer token from the Authorization
+// header of r and resolves it to a subject using validator.
+func SubjectFromRequest(r *http.Request, validator TokenValidator) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", fmt.Errorf("missing Authorization header")
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", fmt.Errorf("Authorization header must use the Bearer scheme")
+	}
+
+	return validator.Validate(strings.TrimPrefix(header, prefix))
+}
+
+// StaticTokenValidator validates tokens against a file of "token subject"
+// pairs, one per line, for operators who would rather hand out
+// long-lived pre-shared tokens than run a JWT issuer.
+type StaticTokenValidator struct {
+	tokens map[string]string
+}
+
+// NewStaticTokenValidator loads tokenFilePath, a file of "<token>
+// <subject>" lines.
+func NewStaticTokenValidator(tokenFilePath string) (*StaticTokenValidator, error) {
+	f, err := os.Open(tokenFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token file %q: %s", tokenFilePath, err)
+	}
+	defer f.Close()
+
+	tokens := map[string]string{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid token file line: %q", line)
+		}
+
+		tokens[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &StaticTokenValidator{tokens: tokens}, nil
+}
+
+// Validate implements TokenValidator.
+func (v *StaticTokenValidator) Validate(token string) (string, error) {
+	subject, ok := v.tokens[token]
+	if !ok {
+		return "", fmt.Errorf("unknown token")
+	}
+
+	return subject, nil
+}
+
+// JWTValidator validates JSON Web Tokens signed with a shared secret, as
+// minted by MakeAdminTokenHandler.
+type JWTValidator struct {
+	signingSecret []byte
+}
+
+// NewJWTValidator creates a JWTValidator that verifies tokens with
+// signingSecret.
+func NewJWTValidator(signingSecret []byte) *JWTValidator {
+	return &JWTValidator{signingSecret: signingSecret}
+}
+
+// Validate implements TokenValidator.
+func (v *JWTValidator) Validate(token string) (string, error) {
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return v.signingSecret, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid token: %s", err)
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return "", fmt.Errorf("invalid token claims")
+	}
+
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return "", fmt.Errorf("token missing subject claim")
+	}
+
+	return subject, nil
+}
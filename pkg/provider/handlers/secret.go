@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -8,18 +11,32 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd"
 	"github.com/openfaas/faas-provider/types"
+
+	"github.com/openfaas/faasd/pkg/audit"
+	"github.com/openfaas/faasd/pkg/metrics"
+	"github.com/openfaas/faasd/pkg/provider/handlers/auth"
+	"github.com/openfaas/faasd/pkg/secrets"
+	"github.com/openfaas/faasd/pkg/secrets/rotation"
+	"github.com/openfaas/faasd/pkg/secrets/source"
 )
 
-const secretFilePermission = 0644
+const secretFilePermission = 0600
 const secretDirPermission = 0755
 
-func MakeSecretHandler(c *containerd.Client, mountPath string) func(w http.ResponseWriter, r *http.Request) {
+// secretMetaDir is the per-namespace subdirectory holding the encryption
+// Header and rotation Metadata for every secret version. listSecrets
+// skips it by name, via isSecretMetaEntry.
+const secretMetaDir = ".secrets-meta"
+
+func MakeSecretHandler(c *containerd.Client, mountPath string, secretDriver secrets.Driver, authz *auth.Authorizer, sourceRegistry *source.Registry, auditLogger *audit.Logger) func(w http.ResponseWriter, r *http.Request) {
 
-	err := os.MkdirAll(mountPath, secretFilePermission)
+	err := os.MkdirAll(mountPath, secretDirPermission)
 	if err != nil {
 		log.Printf("Creating path: %s, error: %s\n", mountPath, err)
 	}
@@ -31,13 +48,13 @@ func MakeSecretHandler(c *containerd.Client, mountPath string) func(w http.Respo
 
 		switch r.Method {
 		case http.MethodGet:
-			listSecrets(c, w, r, mountPath)
+			listSecrets(c, w, r, mountPath, authz, auditLogger)
 		case http.MethodPost:
-			createSecret(c, w, r, mountPath)
+			createSecret(c, w, r, mountPath, secretDriver, authz, sourceRegistry, auditLogger)
 		case http.MethodPut:
-			createSecret(c, w, r, mountPath)
+			createSecret(c, w, r, mountPath, secretDriver, authz, sourceRegistry, auditLogger)
 		case http.MethodDelete:
-			deleteSecret(c, w, r, mountPath)
+			deleteSecret(c, w, r, mountPath, authz, auditLogger)
 		default:
 			w.WriteHeader(http.StatusBadRequest)
 			return
@@ -46,18 +63,151 @@ func MakeSecretHandler(c *containerd.Client, mountPath string) func(w http.Respo
 	}
 }
 
-func listSecrets(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string) {
+// recordSecretOp publishes Prometheus metrics and an audit.Entry for a
+// single secret operation. It is called via defer from each handler so
+// every code path, including early returns, is observed exactly once.
+func recordSecretOp(auditLogger *audit.Logger, start time.Time, op, namespace, secretName, subject string, r *http.Request, err error) {
+	metrics.SecretOpDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+
+	result := audit.OutcomeSuccess
+	errMsg := ""
+	if err != nil {
+		result = audit.OutcomeFailure
+		errMsg = err.Error()
+	}
+
+	metrics.SecretOpsTotal.WithLabelValues(op, result).Inc()
+
+	auditLogger.Record(audit.Entry{
+		Operation: op,
+		Namespace: namespace,
+		Secret:    secretName,
+		Subject:   subject,
+		RequestID: r.Header.Get("X-Call-Id"),
+		Outcome:   result,
+		Error:     errMsg,
+	})
+}
+
+// MakeSecretRotateHandler re-encrypts every secret in every openfaas
+// namespace under newDriver, replacing its stored Header. oldDriver is
+// used to open the existing ciphertext; it is the caller's responsibility
+// to ensure oldDriver can still decrypt secrets sealed under the
+// currently configured driver before rotating. This only rotates the
+// encryption key; it leaves secret content versions untouched.
+func MakeSecretRotateHandler(c *containerd.Client, mountPath string, oldDriver, newDriver secrets.Driver) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		namespaces, err := openfaasNamespaces(r.Context(), c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rotated := []string{}
+		for _, ns := range namespaces {
+			namespacePath := getNamespaceSecretMountPath(mountPath, ns)
+			names, err := rotateSecretKeys(namespacePath, oldDriver, newDriver)
+			if err != nil {
+				log.Printf("[secret] rotate error in namespace %s: %s", ns, err.Error())
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			rotated = append(rotated, names...)
+		}
+
+		bytesOut, _ := json.Marshal(rotated)
+		w.Write(bytesOut)
+	}
+}
+
+// openfaasNamespaces lists every containerd namespace labelled for use
+// by openfaas, the same scope MakeSecretRotateHandler's doc comment
+// promises to rotate.
+func openfaasNamespaces(ctx context.Context, c *containerd.Client) ([]string, error) {
+	all, err := c.NamespaceService().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %s", err)
+	}
+
+	openfaasNs := []string{}
+	for _, ns := range all {
+		valid, err := validNamespace(c, ns)
+		if err != nil {
+			return nil, err
+		}
+		if valid {
+			openfaasNs = append(openfaasNs, ns)
+		}
+	}
+
+	return openfaasNs, nil
+}
+
+// secretStatusProvider is implemented by *rotation.Reconciler. It is
+// declared here, rather than importing the concrete type into the
+// handler signature, so this package only depends on the reconciler's
+// read-only status API.
+type secretStatusProvider interface {
+	Status(namespace, name string) (rotation.Status, bool)
+}
 
+// MakeSecretStatusHandler serves GET /system/secrets/{name}/status with
+// the last known rotation outcome for a secret, as tracked by the
+// rotation reconciler.
+func MakeSecretStatusHandler(statusProvider secretStatusProvider, secretNameFromPath func(*http.Request) string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		namespace := getRequestNamespace(readNamespaceFromQuery(r))
+		name := secretNameFromPath(r)
+
+		status, ok := statusProvider.Status(namespace, name)
+		if !ok {
+			http.Error(w, "no rotation status for secret", http.StatusNotFound)
+			return
+		}
+
+		bytesOut, _ := json.Marshal(status)
+		w.Write(bytesOut)
+	}
+}
+
+func listSecrets(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string, authz *auth.Authorizer, auditLogger *audit.Logger) {
+
+	start := time.Now()
 	lookupNamespace := getRequestNamespace(readNamespaceFromQuery(r))
+
+	var opErr error
+	var subject string
+	defer func() {
+		recordSecretOp(auditLogger, start, "list", lookupNamespace, "", subject, r, opErr)
+	}()
+
+	subject, opErr = authz.Authorize(r, lookupNamespace, auth.ActionRead)
+	if opErr != nil {
+		http.Error(w, opErr.Error(), http.StatusForbidden)
+		return
+	}
+
 	// Check if namespace exists, and it has the openfaas label
 	valid, err := validNamespace(c, lookupNamespace)
 	if err != nil {
+		opErr = err
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	if !valid {
-		http.Error(w, "namespace not valid", http.StatusBadRequest)
+		opErr = fmt.Errorf("namespace not valid")
+		http.Error(w, opErr.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -65,73 +215,123 @@ func listSecrets(c *containerd.Client, w http.ResponseWriter, r *http.Request, m
 
 	files, err := ioutil.ReadDir(mountPath)
 	if err != nil {
+		opErr = err
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	secrets := []types.Secret{}
+	secretList := []types.Secret{}
 	for _, f := range files {
-		secrets = append(secrets, types.Secret{Name: f.Name(), Namespace: lookupNamespace})
+		if isSecretMetaEntry(f.Name()) || isVersionedSecretFile(f.Name()) {
+			continue
+		}
+		secretList = append(secretList, types.Secret{Name: f.Name(), Namespace: lookupNamespace})
 	}
 
-	bytesOut, _ := json.Marshal(secrets)
+	bytesOut, _ := json.Marshal(secretList)
 	w.Write(bytesOut)
 }
 
-func createSecret(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string) {
+func createSecret(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string, secretDriver secrets.Driver, authz *auth.Authorizer, sourceRegistry *source.Registry, auditLogger *audit.Logger) {
+	start := time.Now()
+
 	secret, err := parseSecret(r)
 	if err != nil {
 		log.Printf("[secret] error %s", err.Error())
+		recordSecretOp(auditLogger, start, "create", "", "", "", r, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	err = validateSecret(secret)
-	if err != nil {
-		log.Printf("[secret] error %s", err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	var opErr error
+	var subject string
+	namespace := getRequestNamespace(secret.Namespace)
+	defer func() {
+		recordSecretOp(auditLogger, start, "create", namespace, secret.Name, subject, r, opErr)
+	}()
+
+	opErr = validateSecret(secret)
+	if opErr != nil {
+		log.Printf("[secret] error %s", opErr.Error())
+		http.Error(w, opErr.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subject, opErr = authz.Authorize(r, namespace, auth.ActionWrite)
+	if opErr != nil {
+		http.Error(w, opErr.Error(), http.StatusForbidden)
 		return
 	}
 
 	log.Printf("[secret] is valid: %q", secret.Name)
-	namespace := getRequestNamespace(secret.Namespace)
 	mountPath = getNamespaceSecretMountPath(mountPath, namespace)
 
-	err = os.MkdirAll(mountPath, secretDirPermission)
-	if err != nil {
-		log.Printf("[secret] error %s", err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	opErr = os.MkdirAll(path.Join(mountPath, secretMetaDir), secretDirPermission)
+	if opErr != nil {
+		log.Printf("[secret] error %s", opErr.Error())
+		http.Error(w, opErr.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	data := secret.RawValue
+	isReference := false
 	if len(data) == 0 {
 		data = []byte(secret.Value)
+		isReference = sourceRegistry != nil && sourceRegistry.IsReference(secret.Value)
 	}
 
-	err = ioutil.WriteFile(path.Join(mountPath, secret.Name), data, secretFilePermission)
-
+	ciphertext, header, err := secretDriver.Seal(data)
 	if err != nil {
+		opErr = err
+		log.Printf("[secret] error %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeSecretVersion(mountPath, secret.Name, ciphertext, header); err != nil {
+		opErr = err
+		log.Printf("[secret] error %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := setSecretIsReference(mountPath, secret.Name, isReference); err != nil {
+		opErr = err
 		log.Printf("[secret] error %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-func deleteSecret(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string) {
+func deleteSecret(c *containerd.Client, w http.ResponseWriter, r *http.Request, mountPath string, authz *auth.Authorizer, auditLogger *audit.Logger) {
+	start := time.Now()
+
 	secret, err := parseSecret(r)
 	if err != nil {
 		log.Printf("[secret] error %s", err.Error())
+		recordSecretOp(auditLogger, start, "delete", "", "", "", r, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	namespace := getRequestNamespace(readNamespaceFromQuery(r))
-	mountPath = getNamespaceSecretMountPath(mountPath, namespace)
 
-	err = os.Remove(path.Join(mountPath, secret.Name))
+	var opErr error
+	var subject string
+	defer func() {
+		recordSecretOp(auditLogger, start, "delete", namespace, secret.Name, subject, r, opErr)
+	}()
 
-	if err != nil {
+	subject, opErr = authz.Authorize(r, namespace, auth.ActionWrite)
+	if opErr != nil {
+		http.Error(w, opErr.Error(), http.StatusForbidden)
+		return
+	}
+
+	mountPath = getNamespaceSecretMountPath(mountPath, namespace)
+
+	if err := deleteSecretVersions(mountPath, secret.Name); err != nil {
+		opErr = err
 		log.Printf("[secret] error %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -167,5 +367,371 @@ func validateSecret(secret types.Secret) error {
 	if isTraversal(secret.Name) {
 		return fmt.Errorf(traverseErrorSt)
 	}
+	if isVersionedSecretFile(secret.Name) {
+		return fmt.Errorf("secret name %q collides with the internal version file pattern", secret.Name)
+	}
+	return nil
+}
+
+func isSecretMetaEntry(name string) bool {
+	return name == secretMetaDir
+}
+
+// isVersionedSecretFile reports whether name is a versioned secret
+// payload such as "mysecret.v3", rather than the "current" symlink
+// (plain "mysecret") that listSecrets should surface.
+func isVersionedSecretFile(name string) bool {
+	idx := strings.LastIndex(name, ".v")
+	if idx == -1 {
+		return false
+	}
+
+	suffix := name[idx+2:]
+	if suffix == "" {
+		return false
+	}
+
+	if _, err := strconv.Atoi(suffix); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func versionedSecretName(name string, version int) string {
+	return fmt.Sprintf("%s.v%d", name, version)
+}
+
+func secretHeaderPath(mountPath, versionedName string) string {
+	return path.Join(mountPath, secretMetaDir, versionedName+".header.json")
+}
+
+func secretVersionMetadataPath(mountPath, name string) string {
+	return path.Join(mountPath, secretMetaDir, name+".metadata.json")
+}
+
+func secretReferenceMarkerPath(mountPath, name string) string {
+	return path.Join(mountPath, secretMetaDir, name+".reference")
+}
+
+// setSecretIsReference records whether name's decrypted content is a
+// source.Registry reference (e.g. "vault://...") rather than literal
+// secret material, so that stageFunctionSecrets knows to resolve it
+// through the registry when staging it.
+func setSecretIsReference(mountPath, name string, isReference bool) error {
+	markerPath := secretReferenceMarkerPath(mountPath, name)
+
+	if !isReference {
+		if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to clear reference marker for %q: %s", name, err)
+		}
+		return nil
+	}
+
+	return ioutil.WriteFile(markerPath, []byte{}, secretFilePermission)
+}
+
+// isSecretReference reports whether name's decrypted content should be
+// resolved through a source.Registry rather than used as-is.
+func isSecretReference(mountPath, name string) bool {
+	_, err := os.Stat(secretReferenceMarkerPath(mountPath, name))
+	return err == nil
+}
+
+func writeSecretHeader(mountPath, versionedName string, header secrets.Header) error {
+	bytesOut, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("unable to encode header for %q: %s", versionedName, err)
+	}
+
+	return ioutil.WriteFile(secretHeaderPath(mountPath, versionedName), bytesOut, secretFilePermission)
+}
+
+func readSecretHeader(mountPath, versionedName string) (secrets.Header, error) {
+	header := secrets.Header{}
+
+	raw, err := ioutil.ReadFile(secretHeaderPath(mountPath, versionedName))
+	if err != nil {
+		return header, fmt.Errorf("unable to read header for %q: %s", versionedName, err)
+	}
+
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return header, fmt.Errorf("unable to decode header for %q: %s", versionedName, err)
+	}
+
+	return header, nil
+}
+
+func readVersionMetadata(mountPath, name string) (rotation.Metadata, error) {
+	meta := rotation.Metadata{}
+
+	raw, err := ioutil.ReadFile(secretVersionMetadataPath(mountPath, name))
+	if err != nil {
+		return meta, fmt.Errorf("unable to read metadata for %q: %s", name, err)
+	}
+
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return meta, fmt.Errorf("unable to decode metadata for %q: %s", name, err)
+	}
+
+	return meta, nil
+}
+
+func writeVersionMetadata(mountPath, name string, meta rotation.Metadata) error {
+	bytesOut, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("unable to encode metadata for %q: %s", name, err)
+	}
+
+	return ioutil.WriteFile(secretVersionMetadataPath(mountPath, name), bytesOut, secretFilePermission)
+}
+
+// latestVersion returns the highest existing ".vN" suffix for name, or 0
+// if no version has been written yet.
+func latestVersion(mountPath, name string) (int, error) {
+	files, err := ioutil.ReadDir(mountPath)
+	if err != nil {
+		return 0, err
+	}
+
+	prefix := name + ".v"
+	latest := 0
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), prefix) {
+			continue
+		}
+
+		version, err := strconv.Atoi(strings.TrimPrefix(f.Name(), prefix))
+		if err != nil {
+			continue
+		}
+
+		if version > latest {
+			latest = version
+		}
+	}
+
+	return latest, nil
+}
+
+// writeSecretVersion persists a new version of name, records its
+// rotation Metadata, and repoints the "current" symlink at it, so
+// readers of the plain secret.Name path keep seeing the latest value.
+func writeSecretVersion(mountPath, name string, ciphertext []byte, header secrets.Header) error {
+	latest, err := latestVersion(mountPath, name)
+	if err != nil {
+		return fmt.Errorf("unable to determine current version of %q: %s", name, err)
+	}
+	version := latest + 1
+	versionedName := versionedSecretName(name, version)
+
+	if err := writeSecretHeader(mountPath, versionedName, header); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path.Join(mountPath, versionedName), ciphertext, secretFilePermission); err != nil {
+		return fmt.Errorf("unable to write secret %q: %s", versionedName, err)
+	}
+
+	checksum := sha256.Sum256(ciphertext)
+	meta := rotation.Metadata{
+		CreatedAt: time.Now(),
+		Version:   version,
+		Checksum:  hex.EncodeToString(checksum[:]),
+	}
+	if err := writeVersionMetadata(mountPath, name, meta); err != nil {
+		return err
+	}
+
+	// Swap the "current" symlink via a temp name + rename, rather than
+	// remove-then-symlink, so concurrent readers (stageFunctionSecrets,
+	// exportSecretsToTar, the rotation reconciler) never observe a window
+	// where mountPath/name doesn't exist.
+	currentPath := path.Join(mountPath, name)
+	tmpPath := path.Join(mountPath, "."+name+".tmp")
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to clear stale temp symlink for %q: %s", name, err)
+	}
+
+	if err := os.Symlink(versionedName, tmpPath); err != nil {
+		return fmt.Errorf("unable to create current symlink for %q: %s", name, err)
+	}
+
+	if err := os.Rename(tmpPath, currentPath); err != nil {
+		return fmt.Errorf("unable to swap current symlink for %q: %s", name, err)
+	}
+
 	return nil
 }
+
+// deleteSecretVersions removes every version of name, its metadata, and
+// the "current" symlink pointing at them.
+func deleteSecretVersions(mountPath, name string) error {
+	files, err := ioutil.ReadDir(mountPath)
+	if err != nil {
+		return err
+	}
+
+	prefix := name + ".v"
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name(), prefix) || !isVersionedSecretFile(f.Name()) {
+			continue
+		}
+
+		if _, err := strconv.Atoi(strings.TrimPrefix(f.Name(), prefix)); err != nil {
+			continue
+		}
+
+		if err := os.Remove(path.Join(mountPath, f.Name())); err != nil {
+			return fmt.Errorf("unable to remove %q: %s", f.Name(), err)
+		}
+
+		if err := os.Remove(secretHeaderPath(mountPath, f.Name())); err != nil && !os.IsNotExist(err) {
+			log.Printf("[secret] error removing header for %q: %s", f.Name(), err.Error())
+		}
+	}
+
+	if err := os.Remove(secretVersionMetadataPath(mountPath, name)); err != nil && !os.IsNotExist(err) {
+		log.Printf("[secret] error removing metadata for %q: %s", name, err.Error())
+	}
+
+	if err := os.Remove(secretReferenceMarkerPath(mountPath, name)); err != nil && !os.IsNotExist(err) {
+		log.Printf("[secret] error removing reference marker for %q: %s", name, err.Error())
+	}
+
+	if err := os.Remove(path.Join(mountPath, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove %q: %s", name, err)
+	}
+
+	return nil
+}
+
+// rotateSecretKeys re-encrypts the current version of every secret under
+// namespacePath with newDriver, leaving its version number and creation
+// time unchanged; only the checksum in its metadata is updated.
+func rotateSecretKeys(namespacePath string, oldDriver, newDriver secrets.Driver) ([]string, error) {
+	files, err := ioutil.ReadDir(namespacePath)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := []string{}
+	for _, f := range files {
+		if isSecretMetaEntry(f.Name()) || isVersionedSecretFile(f.Name()) {
+			continue
+		}
+
+		name := f.Name()
+
+		versionedName, err := os.Readlink(path.Join(namespacePath, name))
+		if err != nil {
+			return rotated, fmt.Errorf("unable to resolve current version of %q: %s", name, err)
+		}
+
+		header, err := readSecretHeader(namespacePath, versionedName)
+		if err != nil {
+			return rotated, err
+		}
+
+		ciphertext, err := ioutil.ReadFile(path.Join(namespacePath, versionedName))
+		if err != nil {
+			return rotated, fmt.Errorf("unable to read secret %q: %s", versionedName, err)
+		}
+
+		plaintext, err := oldDriver.Open(ciphertext, header)
+		if err != nil {
+			return rotated, fmt.Errorf("unable to decrypt secret %q for rotation: %s", versionedName, err)
+		}
+
+		newCiphertext, newHeader, err := newDriver.Seal(plaintext)
+		if err != nil {
+			return rotated, fmt.Errorf("unable to re-encrypt secret %q: %s", versionedName, err)
+		}
+
+		if err := writeSecretHeader(namespacePath, versionedName, newHeader); err != nil {
+			return rotated, err
+		}
+
+		if err := ioutil.WriteFile(path.Join(namespacePath, versionedName), newCiphertext, secretFilePermission); err != nil {
+			return rotated, fmt.Errorf("unable to write rotated secret %q: %s", versionedName, err)
+		}
+
+		meta, err := readVersionMetadata(namespacePath, name)
+		if err != nil {
+			return rotated, err
+		}
+		checksum := sha256.Sum256(newCiphertext)
+		meta.Checksum = hex.EncodeToString(checksum[:])
+		if err := writeVersionMetadata(namespacePath, name, meta); err != nil {
+			return rotated, err
+		}
+
+		rotated = append(rotated, name)
+	}
+
+	return rotated, nil
+}
+
+// secretContentRotator implements rotation.SecretRotator: it is called by
+// the rotation reconciler once a secret's Metadata shows it has exceeded
+// ttl, and seals a fresh version of the same plaintext under a new
+// ciphertext/nonce so a compromised version can't be replayed.
+type secretContentRotator struct {
+	mountPath string
+	driver    secrets.Driver
+}
+
+// NewSecretContentRotator builds a rotation.SecretRotator that versions
+// secrets in mountPath using driver.
+func NewSecretContentRotator(mountPath string, driver secrets.Driver) rotation.SecretRotator {
+	return &secretContentRotator{mountPath: mountPath, driver: driver}
+}
+
+func (s *secretContentRotator) Rotate(namespace, name string, ttl time.Duration, now time.Time) (bool, int, error) {
+	namespacePath := getNamespaceSecretMountPath(s.mountPath, namespace)
+
+	meta, err := readVersionMetadata(namespacePath, name)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if !meta.Expired(now, ttl) {
+		return false, meta.Version, nil
+	}
+
+	versionedName, err := os.Readlink(path.Join(namespacePath, name))
+	if err != nil {
+		return false, meta.Version, fmt.Errorf("unable to resolve current version of %q: %s", name, err)
+	}
+
+	header, err := readSecretHeader(namespacePath, versionedName)
+	if err != nil {
+		return false, meta.Version, err
+	}
+
+	ciphertext, err := ioutil.ReadFile(path.Join(namespacePath, versionedName))
+	if err != nil {
+		return false, meta.Version, fmt.Errorf("unable to read secret %q: %s", versionedName, err)
+	}
+
+	plaintext, err := s.driver.Open(ciphertext, header)
+	if err != nil {
+		return false, meta.Version, fmt.Errorf("unable to decrypt secret %q for rotation: %s", versionedName, err)
+	}
+
+	newCiphertext, newHeader, err := s.driver.Seal(plaintext)
+	if err != nil {
+		return false, meta.Version, fmt.Errorf("unable to re-encrypt secret %q: %s", name, err)
+	}
+
+	if err := writeSecretVersion(namespacePath, name, newCiphertext, newHeader); err != nil {
+		return false, meta.Version, err
+	}
+
+	newMeta, err := readVersionMetadata(namespacePath, name)
+	if err != nil {
+		return false, meta.Version, err
+	}
+
+	return true, newMeta.Version, nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/namespaces"
+
+	"github.com/openfaas/faasd/pkg/secrets/rotation"
+)
+
+// secretsLabel lists the names of the secrets mounted into a function,
+// comma-separated, so containerdNamespaceLister can tell the reconciler
+// which secrets to watch without parsing the function's OCI spec.
+const secretsLabel = "com.openfaas.secrets"
+
+// containerdNamespaceLister implements rotation.NamespaceLister directly
+// against containerd, reusing openfaasNamespaces' notion of which
+// namespaces are in scope for rotation.
+type containerdNamespaceLister struct {
+	client *containerd.Client
+}
+
+// NewContainerdNamespaceLister builds a rotation.NamespaceLister backed
+// by client.
+func NewContainerdNamespaceLister(client *containerd.Client) rotation.NamespaceLister {
+	return &containerdNamespaceLister{client: client}
+}
+
+// Namespaces implements rotation.NamespaceLister.
+func (l *containerdNamespaceLister) Namespaces(ctx context.Context) ([]string, error) {
+	return openfaasNamespaces(ctx, l.client)
+}
+
+// Functions implements rotation.NamespaceLister, reading each function's
+// rotation TTL and mounted secrets from its container labels.
+func (l *containerdNamespaceLister) Functions(ctx context.Context, namespace string) ([]rotation.Function, error) {
+	ctx = namespaces.WithNamespace(ctx, namespace)
+
+	containers, err := l.client.Containers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list containers in %s: %s", namespace, err)
+	}
+
+	functions := []rotation.Function{}
+	for _, c := range containers {
+		labels, err := c.Labels(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read labels for %s: %s", c.ID(), err)
+		}
+
+		fn, ok, err := functionFromLabels(c.ID(), labels)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		functions = append(functions, fn)
+	}
+
+	return functions, nil
+}
+
+// functionFromLabels builds a rotation.Function from a container's
+// labels, returning ok=false for containers that don't opt into
+// rotation via rotation.RotateLabel.
+func functionFromLabels(name string, labels map[string]string) (rotation.Function, bool, error) {
+	ttlLabel, ok := labels[rotation.RotateLabel]
+	if !ok {
+		return rotation.Function{}, false, nil
+	}
+
+	ttl, err := time.ParseDuration(ttlLabel)
+	if err != nil {
+		return rotation.Function{}, false, fmt.Errorf("invalid %s label %q on %s: %s", rotation.RotateLabel, ttlLabel, name, err)
+	}
+
+	var secretNames []string
+	if raw := labels[secretsLabel]; raw != "" {
+		secretNames = strings.Split(raw, ",")
+	}
+
+	return rotation.Function{
+		Name:    name,
+		Secrets: secretNames,
+		TTL:     ttl,
+	}, true, nil
+}
+
+// containerdTaskRestarter signals a function's containerd task with
+// SIGHUP so it reloads its mounted secrets after the rotation reconciler
+// has rotated one of them.
+type containerdTaskRestarter struct {
+	client *containerd.Client
+}
+
+// NewContainerdTaskRestarter builds a rotation.TaskRestarter backed by
+// client.
+func NewContainerdTaskRestarter(client *containerd.Client) rotation.TaskRestarter {
+	return &containerdTaskRestarter{client: client}
+}
+
+// RestartFunction implements rotation.TaskRestarter.
+func (t *containerdTaskRestarter) RestartFunction(ctx context.Context, namespace, name string) error {
+	ctx = namespaces.WithNamespace(ctx, namespace)
+
+	container, err := t.client.LoadContainer(ctx, name)
+	if err != nil {
+		return fmt.Errorf("unable to load container %q: %s", name, err)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to load task for %q: %s", name, err)
+	}
+
+	if err := task.Kill(ctx, syscall.SIGHUP); err != nil {
+		return fmt.Errorf("unable to signal task %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import "testing"
+
+func TestFunctionFromLabelsSkipsContainersWithoutRotateLabel(t *testing.T) {
+	_, ok, err := functionFromLabels("fn1", map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Fatalf("expected ok=false for a container with no rotate label")
+	}
+}
+
+func TestFunctionFromLabelsParsesTTLAndSecrets(t *testing.T) {
+	fn, ok, err := functionFromLabels("fn1", map[string]string{
+		"com.openfaas.secret.rotate": "24h",
+		secretsLabel:                 "db-password,api-key",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected ok=true")
+	}
+	if fn.Name != "fn1" {
+		t.Fatalf("got name %q, want %q", fn.Name, "fn1")
+	}
+	if fn.TTL.Hours() != 24 {
+		t.Fatalf("got ttl %s, want 24h", fn.TTL)
+	}
+	if len(fn.Secrets) != 2 || fn.Secrets[0] != "db-password" || fn.Secrets[1] != "api-key" {
+		t.Fatalf("got secrets %v, want [db-password api-key]", fn.Secrets)
+	}
+}
+
+func TestFunctionFromLabelsRejectsInvalidTTL(t *testing.T) {
+	_, _, err := functionFromLabels("fn1", map[string]string{
+		"com.openfaas.secret.rotate": "not-a-duration",
+	})
+	if err == nil {
+		t.Fatalf("expected an error for an unparseable rotate label")
+	}
+}
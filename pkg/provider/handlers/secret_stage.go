@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/openfaas/faasd/pkg/secrets"
+	"github.com/openfaas/faasd/pkg/secrets/source"
+)
+
+// stageFunctionSecrets decrypts the named secrets from namespacePath and
+// writes their plaintext into stagingPath, which the caller is expected
+// to have mounted as tmpfs. This keeps the durable, on-disk copy of every
+// secret encrypted at rest while still satisfying functions that expect
+// to read plaintext from their secret mount at startup. Secrets created
+// from an external reference (e.g. "vault://...") are resolved through
+// sourceRegistry instead of being staged as-is.
+func stageFunctionSecrets(ctx context.Context, namespacePath, stagingPath string, names []string, secretDriver secrets.Driver, sourceRegistry *source.Registry) error {
+	for _, name := range names {
+		versionedName, err := os.Readlink(path.Join(namespacePath, name))
+		if err != nil {
+			return fmt.Errorf("unable to resolve current version of %q: %s", name, err)
+		}
+
+		header, err := readSecretHeader(namespacePath, versionedName)
+		if err != nil {
+			return err
+		}
+
+		ciphertext, err := ioutil.ReadFile(path.Join(namespacePath, versionedName))
+		if err != nil {
+			return fmt.Errorf("unable to read secret %q: %s", name, err)
+		}
+
+		plaintext, err := secretDriver.Open(ciphertext, header)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt secret %q: %s", name, err)
+		}
+
+		if isSecretReference(namespacePath, name) {
+			if sourceRegistry == nil {
+				return fmt.Errorf("secret %q is a reference but no source registry is configured", name)
+			}
+
+			plaintext, err = sourceRegistry.Resolve(ctx, string(plaintext))
+			if err != nil {
+				return fmt.Errorf("unable to resolve secret %q: %s", name, err)
+			}
+		}
+
+		if err := ioutil.WriteFile(path.Join(stagingPath, name), plaintext, secretFilePermission); err != nil {
+			return fmt.Errorf("unable to stage secret %q: %s", name, err)
+		}
+	}
+
+	return nil
+}
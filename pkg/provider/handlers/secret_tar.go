@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/containerd/containerd"
+	"github.com/openfaas/faas-provider/types"
+
+	"github.com/openfaas/faasd/pkg/provider/handlers/auth"
+	"github.com/openfaas/faasd/pkg/secrets"
+)
+
+// Pax header keys used to carry faasd-specific metadata on each tar
+// entry, alongside the usual name/size/mode fields.
+const (
+	paxSecretNamespace = "FAASD.namespace"
+)
+
+// secretDiffEntry describes what an import would do to a single secret,
+// as returned for ?dry-run=true.
+type secretDiffEntry struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// MakeSecretExportHandler serves GET /system/secrets/export: a tar
+// archive of every secret's current, decrypted value in the requested
+// namespace. Pass ?gzip=true to compress the stream, and
+// ?recipient=age1... to encrypt it to an age X25519 recipient.
+func MakeSecretExportHandler(c *containerd.Client, mountPath string, secretDriver secrets.Driver, authz *auth.Authorizer) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		namespace := getRequestNamespace(readNamespaceFromQuery(r))
+		if _, err := authz.Authorize(r, namespace, auth.ActionRead); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		valid, err := validNamespace(c, namespace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !valid {
+			http.Error(w, "namespace not valid", http.StatusBadRequest)
+			return
+		}
+
+		namespacePath := getNamespaceSecretMountPath(mountPath, namespace)
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+
+		var out io.Writer = w
+
+		if recipientStr := r.URL.Query().Get("recipient"); recipientStr != "" {
+			recipient, err := age.ParseX25519Recipient(recipientStr)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid age recipient: %s", err), http.StatusBadRequest)
+				return
+			}
+
+			ageWriter, err := age.Encrypt(out, recipient)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("unable to start age encryption: %s", err), http.StatusInternalServerError)
+				return
+			}
+			defer ageWriter.Close()
+			out = ageWriter
+		}
+
+		if r.URL.Query().Get("gzip") == "true" {
+			gzWriter := gzip.NewWriter(out)
+			defer gzWriter.Close()
+			out = gzWriter
+		}
+
+		tw := tar.NewWriter(out)
+		defer tw.Close()
+
+		if err := exportSecretsToTar(tw, namespacePath, namespace, secretDriver); err != nil {
+			log.Printf("[secret] export error %s", err.Error())
+		}
+	}
+}
+
+func exportSecretsToTar(tw *tar.Writer, namespacePath, namespace string, secretDriver secrets.Driver) error {
+	files, err := ioutil.ReadDir(namespacePath)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if isSecretMetaEntry(f.Name()) || isVersionedSecretFile(f.Name()) {
+			continue
+		}
+
+		name := f.Name()
+
+		versionedName, err := os.Readlink(path.Join(namespacePath, name))
+		if err != nil {
+			return fmt.Errorf("unable to resolve current version of %q: %s", name, err)
+		}
+
+		header, err := readSecretHeader(namespacePath, versionedName)
+		if err != nil {
+			return err
+		}
+
+		ciphertext, err := ioutil.ReadFile(path.Join(namespacePath, versionedName))
+		if err != nil {
+			return fmt.Errorf("unable to read secret %q: %s", versionedName, err)
+		}
+
+		plaintext, err := secretDriver.Open(ciphertext, header)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt secret %q: %s", name, err)
+		}
+
+		tarHeader := &tar.Header{
+			Name: name,
+			Mode: secretFilePermission,
+			Size: int64(len(plaintext)),
+			PAXRecords: map[string]string{
+				paxSecretNamespace: namespace,
+			},
+		}
+
+		if err := tw.WriteHeader(tarHeader); err != nil {
+			return fmt.Errorf("unable to write tar header for %q: %s", name, err)
+		}
+
+		if _, err := tw.Write(plaintext); err != nil {
+			return fmt.Errorf("unable to write tar entry for %q: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// MakeSecretImportHandler serves POST /system/secrets/import: the
+// inverse of export. The request body is a tar stream (optionally
+// age-encrypted to an identity passed via ?identity=AGE-SECRET-KEY-1...,
+// and/or gzip-compressed, selected with ?gzip=true) of secret name/value
+// pairs to create or overwrite in the requested namespace. Pass
+// ?dry-run=true to get back a JSON diff of what would happen without
+// writing anything.
+func MakeSecretImportHandler(c *containerd.Client, mountPath string, secretDriver secrets.Driver, authz *auth.Authorizer) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		namespace := getRequestNamespace(readNamespaceFromQuery(r))
+		if _, err := authz.Authorize(r, namespace, auth.ActionWrite); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		valid, err := validNamespace(c, namespace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !valid {
+			http.Error(w, "namespace not valid", http.StatusBadRequest)
+			return
+		}
+
+		namespacePath := getNamespaceSecretMountPath(mountPath, namespace)
+		if err := os.MkdirAll(path.Join(namespacePath, secretMetaDir), secretDirPermission); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var in io.Reader = r.Body
+
+		if identityStr := r.URL.Query().Get("identity"); identityStr != "" {
+			identity, err := age.ParseX25519Identity(identityStr)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid age identity: %s", err), http.StatusBadRequest)
+				return
+			}
+
+			ageReader, err := age.Decrypt(in, identity)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("unable to start age decryption: %s", err), http.StatusBadRequest)
+				return
+			}
+			in = ageReader
+		}
+
+		if r.URL.Query().Get("gzip") == "true" {
+			gzReader, err := gzip.NewReader(in)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid gzip stream: %s", err), http.StatusBadRequest)
+				return
+			}
+			defer gzReader.Close()
+			in = gzReader
+		}
+
+		dryRun := r.URL.Query().Get("dry-run") == "true"
+
+		diff, err := importSecretsFromTar(in, namespacePath, secretDriver, dryRun)
+		if err != nil {
+			log.Printf("[secret] import error %s", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		bytesOut, _ := json.Marshal(diff)
+		w.Write(bytesOut)
+	}
+}
+
+func importSecretsFromTar(in io.Reader, namespacePath string, secretDriver secrets.Driver, dryRun bool) ([]secretDiffEntry, error) {
+	tr := tar.NewReader(in)
+
+	diff := []secretDiffEntry{}
+
+	for {
+		tarHeader, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return diff, fmt.Errorf("unable to read tar entry: %s", err)
+		}
+
+		if tarHeader.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Validate the entry's original name, not just its basename: a
+		// crafted "../../etc/passwd" must be rejected outright rather than
+		// silently rewritten to "passwd" and imported.
+		name := tarHeader.Name
+		secret := types.Secret{Name: name}
+		if err := validateSecret(secret); err != nil {
+			return diff, fmt.Errorf("invalid entry %q: %s", tarHeader.Name, err)
+		}
+
+		destination := path.Join(namespacePath, name)
+		if !strings.HasPrefix(destination, namespacePath) {
+			return diff, fmt.Errorf("entry %q escapes the secrets mount", tarHeader.Name)
+		}
+
+		action := "create"
+		if _, err := os.Lstat(destination); err == nil {
+			action = "overwrite"
+		}
+		diff = append(diff, secretDiffEntry{Name: name, Action: action})
+
+		if dryRun {
+			continue
+		}
+
+		plaintext, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return diff, fmt.Errorf("unable to read value for %q: %s", name, err)
+		}
+
+		ciphertext, header, err := secretDriver.Seal(plaintext)
+		if err != nil {
+			return diff, fmt.Errorf("unable to encrypt %q: %s", name, err)
+		}
+
+		if err := writeSecretVersion(namespacePath, name, ciphertext, header); err != nil {
+			return diff, err
+		}
+	}
+
+	return diff, nil
+}
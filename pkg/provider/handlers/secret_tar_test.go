@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+
+	"github.com/openfaas/faasd/pkg/secrets"
+)
+
+func newTestSecretDriver(t *testing.T) secrets.Driver {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "master.passphrase")
+	if err := os.WriteFile(path, []byte("correct horse battery staple\n"), 0600); err != nil {
+		t.Fatalf("write passphrase: %s", err)
+	}
+	return secrets.NewPassphraseDriver(path)
+}
+
+// seedSecret writes a sealed secret directly into namespacePath, as if
+// it had been created through createSecret.
+func seedSecret(t *testing.T, namespacePath, name, value string, driver secrets.Driver) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(namespacePath, secretMetaDir), secretDirPermission); err != nil {
+		t.Fatalf("mkdir meta dir: %s", err)
+	}
+
+	ciphertext, header, err := driver.Seal([]byte(value))
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+
+	if err := writeSecretVersion(namespacePath, name, ciphertext, header); err != nil {
+		t.Fatalf("writeSecretVersion: %s", err)
+	}
+}
+
+func TestExportImportTarRoundTrip(t *testing.T) {
+	driver := newTestSecretDriver(t)
+	namespacePath := t.TempDir()
+
+	seedSecret(t, namespacePath, "db-password", "hunter2", driver)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := exportSecretsToTar(tw, namespacePath, "openfaas-fn", driver); err != nil {
+		t.Fatalf("exportSecretsToTar: %s", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %s", err)
+	}
+
+	importPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(importPath, secretMetaDir), secretDirPermission); err != nil {
+		t.Fatalf("mkdir meta dir: %s", err)
+	}
+	diff, err := importSecretsFromTar(&buf, importPath, driver, false)
+	if err != nil {
+		t.Fatalf("importSecretsFromTar: %s", err)
+	}
+	if len(diff) != 1 || diff[0].Name != "db-password" || diff[0].Action != "create" {
+		t.Fatalf("got diff %+v, want a single create for db-password", diff)
+	}
+
+	versionedName, err := os.Readlink(filepath.Join(importPath, "db-password"))
+	if err != nil {
+		t.Fatalf("Readlink: %s", err)
+	}
+	header, err := readSecretHeader(importPath, versionedName)
+	if err != nil {
+		t.Fatalf("readSecretHeader: %s", err)
+	}
+	ciphertext, err := os.ReadFile(filepath.Join(importPath, versionedName))
+	if err != nil {
+		t.Fatalf("read ciphertext: %s", err)
+	}
+	plaintext, err := driver.Open(ciphertext, header)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	if string(plaintext) != "hunter2" {
+		t.Fatalf("got %q, want %q", plaintext, "hunter2")
+	}
+}
+
+func TestExportImportTarAgeRoundTrip(t *testing.T) {
+	driver := newTestSecretDriver(t)
+	namespacePath := t.TempDir()
+	seedSecret(t, namespacePath, "api-key", "topsecret", driver)
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("GenerateX25519Identity: %s", err)
+	}
+
+	var encrypted bytes.Buffer
+	ageWriter, err := age.Encrypt(&encrypted, identity.Recipient())
+	if err != nil {
+		t.Fatalf("age.Encrypt: %s", err)
+	}
+
+	tw := tar.NewWriter(ageWriter)
+	if err := exportSecretsToTar(tw, namespacePath, "openfaas-fn", driver); err != nil {
+		t.Fatalf("exportSecretsToTar: %s", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %s", err)
+	}
+	if err := ageWriter.Close(); err != nil {
+		t.Fatalf("close age writer: %s", err)
+	}
+
+	// The import handler parses ?identity= and wraps the body reader in
+	// age.Decrypt before handing it to importSecretsFromTar; exercise
+	// that same decryption step here.
+	ageReader, err := age.Decrypt(&encrypted, identity)
+	if err != nil {
+		t.Fatalf("age.Decrypt: %s", err)
+	}
+
+	importPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(importPath, secretMetaDir), secretDirPermission); err != nil {
+		t.Fatalf("mkdir meta dir: %s", err)
+	}
+	diff, err := importSecretsFromTar(ageReader, importPath, driver, false)
+	if err != nil {
+		t.Fatalf("importSecretsFromTar: %s", err)
+	}
+	if len(diff) != 1 || diff[0].Name != "api-key" || diff[0].Action != "create" {
+		t.Fatalf("got diff %+v, want a single create for api-key", diff)
+	}
+}
+
+func TestImportSecretsFromTarRejectsTraversalNames(t *testing.T) {
+	driver := newTestSecretDriver(t)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	content := []byte("evil")
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "../../etc/passwd",
+		Mode: 0600,
+		Size: int64(len(content)),
+	}); err != nil {
+		t.Fatalf("WriteHeader: %s", err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %s", err)
+	}
+
+	importPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(importPath, secretMetaDir), secretDirPermission); err != nil {
+		t.Fatalf("mkdir meta dir: %s", err)
+	}
+
+	// A traversal entry must be rejected outright, not sanitized down to
+	// its basename and imported.
+	if _, err := importSecretsFromTar(&buf, importPath, driver, false); err == nil {
+		t.Fatalf("expected a traversal entry to be rejected")
+	}
+	if _, err := os.Lstat(filepath.Join(importPath, "passwd")); err == nil {
+		t.Fatalf("traversal entry must not be imported under any name")
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/openfaas/faas-provider/types"
+)
+
+func TestIsVersionedSecretFile(t *testing.T) {
+	cases := map[string]bool{
+		"mysecret":     false,
+		"mysecret.v1":  true,
+		"mysecret.v42": true,
+		"mysecret.v":   false,
+		"mysecret.vx":  false,
+	}
+
+	for name, want := range cases {
+		if got := isVersionedSecretFile(name); got != want {
+			t.Errorf("isVersionedSecretFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestValidateSecretRejectsVersionedNameCollision(t *testing.T) {
+	err := validateSecret(types.Secret{Name: "mysecret.v3"})
+	if err == nil {
+		t.Fatalf("expected a name colliding with the .vN pattern to be rejected")
+	}
+}
+
+func TestValidateSecretRejectsTraversal(t *testing.T) {
+	err := validateSecret(types.Secret{Name: "../etc/passwd"})
+	if err == nil {
+		t.Fatalf("expected a traversal name to be rejected")
+	}
+}
+
+func TestValidateSecretAcceptsOrdinaryName(t *testing.T) {
+	if err := validateSecret(types.Secret{Name: "db-password"}); err != nil {
+		t.Fatalf("unexpected error for an ordinary secret name: %s", err)
+	}
+}
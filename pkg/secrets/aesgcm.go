@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+)
+
+// AESGCMDriver encrypts secrets with AES-256-GCM using a raw 32-byte key
+// read from a file, for operators who manage keys externally (e.g. a
+// mounted Kubernetes secret or an HSM-backed file) rather than deriving
+// one from a passphrase.
+type AESGCMDriver struct {
+	keyPath string
+}
+
+// NewAESGCMDriver creates an AESGCMDriver that reads its key from keyPath
+// on every Seal/Open call.
+func NewAESGCMDriver(keyPath string) *AESGCMDriver {
+	return &AESGCMDriver{keyPath: keyPath}
+}
+
+// Name implements Driver.
+func (d *AESGCMDriver) Name() string {
+	return "aes-gcm"
+}
+
+func (d *AESGCMDriver) readKey() ([]byte, error) {
+	key, err := ioutil.ReadFile(d.keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key file %q: %s", d.keyPath, err)
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key file %q must contain exactly 32 bytes, got %d", d.keyPath, len(key))
+	}
+
+	return key, nil
+}
+
+func (d *AESGCMDriver) gcm() (cipher.AEAD, error) {
+	key, err := d.readKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher: %s", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Seal implements Driver.
+func (d *AESGCMDriver) Seal(plaintext []byte) ([]byte, Header, error) {
+	gcm, err := d.gcm()
+	if err != nil {
+		return nil, Header{}, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, Header{}, fmt.Errorf("unable to generate nonce: %s", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	header := Header{
+		Driver:  d.Name(),
+		Version: 1,
+		Nonce:   nonce,
+	}
+
+	return ciphertext, header, nil
+}
+
+// Open implements Driver.
+func (d *AESGCMDriver) Open(ciphertext []byte, header Header) ([]byte, error) {
+	gcm, err := d.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(header.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("unexpected nonce size %d", len(header.Nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, header.Nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt secret: %s", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestAESGCMDriver(t *testing.T) *AESGCMDriver {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key")
+	key := make([]byte, 32)
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		t.Fatalf("write key file: %s", err)
+	}
+	return NewAESGCMDriver(path)
+}
+
+func TestAESGCMDriverSealOpenRoundTrip(t *testing.T) {
+	d := newTestAESGCMDriver(t)
+
+	ciphertext, header, err := d.Seal([]byte("super-secret-value"))
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+
+	plaintext, err := d.Open(ciphertext, header)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+
+	if string(plaintext) != "super-secret-value" {
+		t.Fatalf("got %q, want %q", plaintext, "super-secret-value")
+	}
+}
+
+func TestAESGCMDriverRejectsWrongSizedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("too-short"), 0600); err != nil {
+		t.Fatalf("write key file: %s", err)
+	}
+	d := NewAESGCMDriver(path)
+
+	if _, _, err := d.Seal([]byte("value")); err == nil {
+		t.Fatalf("expected Seal to reject a non-32-byte key")
+	}
+}
@@ -0,0 +1,75 @@
+// Package secrets provides pluggable encryption-at-rest for faasd's
+// filesystem-backed secrets store.
+package secrets
+
+import "fmt"
+
+// Header is the on-disk, per-secret metadata that records how a secret
+// was encrypted so that it can later be decrypted or rotated onto a new
+// key without touching the plaintext.
+type Header struct {
+	// Driver is the name of the Driver that sealed this secret.
+	Driver string `json:"driver"`
+
+	// Version allows a driver to evolve its on-disk format over time.
+	Version int `json:"version"`
+
+	// KDF identifies the key-derivation function used, if any.
+	KDF string `json:"kdf,omitempty"`
+
+	// Salt is the KDF salt, when applicable.
+	Salt []byte `json:"salt,omitempty"`
+
+	// Nonce is the cipher nonce/IV used to seal this secret.
+	Nonce []byte `json:"nonce,omitempty"`
+
+	// Params carries driver-specific KDF parameters, such as scrypt's
+	// N/r/p cost factors, so a secret sealed under one cost can still be
+	// opened if the defaults change later.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Driver seals and opens secret values. Implementations are free to choose
+// their own key-management scheme; the Header returned from Seal is
+// persisted alongside the ciphertext and handed back to Open.
+type Driver interface {
+	// Name identifies the driver, as stored in Header.Driver.
+	Name() string
+
+	// Seal encrypts plaintext, returning the ciphertext to persist and
+	// the header describing how to reverse it.
+	Seal(plaintext []byte) (ciphertext []byte, header Header, err error)
+
+	// Open decrypts ciphertext previously produced by Seal using the
+	// accompanying header.
+	Open(ciphertext []byte, header Header) (plaintext []byte, err error)
+}
+
+// NewDriver constructs a Driver from a name and a small set of
+// driver-specific configuration values, as loaded from faasd's
+// configuration. Unknown driver names return an error rather than
+// silently falling back to plaintext storage.
+func NewDriver(name string, config map[string]string) (Driver, error) {
+	switch name {
+	case "", "passphrase":
+		passphrasePath := config["passphrase-file"]
+		if passphrasePath == "" {
+			passphrasePath = "/var/lib/faasd/secrets/master.passphrase"
+		}
+		return NewPassphraseDriver(passphrasePath), nil
+	case "aes-gcm":
+		keyPath := config["key-file"]
+		if keyPath == "" {
+			return nil, fmt.Errorf("aes-gcm driver requires a key-file")
+		}
+		return NewAESGCMDriver(keyPath), nil
+	case "exec":
+		command := config["command"]
+		if command == "" {
+			return nil, fmt.Errorf("exec driver requires a command")
+		}
+		return NewExecDriver(command), nil
+	default:
+		return nil, fmt.Errorf("unknown secret driver: %q", name)
+	}
+}
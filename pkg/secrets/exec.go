@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// ExecDriver delegates sealing and opening to an external command, with
+// "encrypt" or "decrypt" passed on argv and the value on stdin/stdout.
+// This lets an operator plug in a key-management backend faasd has no
+// native driver for, without faasd needing to know anything about it.
+type ExecDriver struct {
+	command string
+}
+
+// NewExecDriver creates an ExecDriver that invokes command with
+// "encrypt" or "decrypt" as its first argument.
+func NewExecDriver(command string) *ExecDriver {
+	return &ExecDriver{command: command}
+}
+
+// Name implements Driver.
+func (d *ExecDriver) Name() string {
+	return "exec"
+}
+
+func (d *ExecDriver) run(op string, input []byte) ([]byte, error) {
+	cmd := exec.Command(d.command, op)
+	cmd.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("external secret driver %q failed: %s: %s", d.command, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Seal implements Driver.
+func (d *ExecDriver) Seal(plaintext []byte) ([]byte, Header, error) {
+	ciphertext, err := d.run("encrypt", plaintext)
+	if err != nil {
+		return nil, Header{}, err
+	}
+
+	return ciphertext, Header{Driver: d.Name(), Version: 1}, nil
+}
+
+// Open implements Driver.
+func (d *ExecDriver) Open(ciphertext []byte, header Header) ([]byte, error) {
+	return d.run("decrypt", ciphertext)
+}
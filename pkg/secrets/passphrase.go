@@ -0,0 +1,144 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	passphraseKDF  = "scrypt"
+	passphraseN    = 1 << 15
+	passphraseR    = 8
+	passphraseP    = 1
+	passphraseSalt = 32
+)
+
+// PassphraseDriver encrypts secrets with NaCl secretbox using a key
+// derived via scrypt from a faasd master passphrase kept on disk. This is
+// the default driver: it needs no external dependencies, only a
+// passphrase file that the operator is responsible for protecting.
+type PassphraseDriver struct {
+	passphrasePath string
+}
+
+// NewPassphraseDriver creates a PassphraseDriver that reads its master
+// passphrase from passphrasePath on every Seal/Open call.
+func NewPassphraseDriver(passphrasePath string) *PassphraseDriver {
+	return &PassphraseDriver{passphrasePath: passphrasePath}
+}
+
+// Name implements Driver.
+func (d *PassphraseDriver) Name() string {
+	return "passphrase"
+}
+
+func (d *PassphraseDriver) readPassphrase() ([]byte, error) {
+	raw, err := ioutil.ReadFile(d.passphrasePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read master passphrase %q: %s", d.passphrasePath, err)
+	}
+	return []byte(strings.TrimSpace(string(raw))), nil
+}
+
+func (d *PassphraseDriver) deriveKey(passphrase, salt []byte, params map[string]string) (*[32]byte, error) {
+	n, r, p := passphraseN, passphraseR, passphraseP
+	if v, ok := params["N"]; ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt N param %q: %s", v, err)
+		}
+		n = parsed
+	}
+	if v, ok := params["r"]; ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt r param %q: %s", v, err)
+		}
+		r = parsed
+	}
+	if v, ok := params["p"]; ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt p param %q: %s", v, err)
+		}
+		p = parsed
+	}
+
+	raw, err := scrypt.Key(passphrase, salt, n, r, p, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive key: %s", err)
+	}
+
+	var key [32]byte
+	copy(key[:], raw)
+	return &key, nil
+}
+
+// Seal implements Driver.
+func (d *PassphraseDriver) Seal(plaintext []byte) ([]byte, Header, error) {
+	passphrase, err := d.readPassphrase()
+	if err != nil {
+		return nil, Header{}, err
+	}
+
+	salt := make([]byte, passphraseSalt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, Header{}, fmt.Errorf("unable to generate salt: %s", err)
+	}
+
+	key, err := d.deriveKey(passphrase, salt, nil)
+	if err != nil {
+		return nil, Header{}, err
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, Header{}, fmt.Errorf("unable to generate nonce: %s", err)
+	}
+
+	ciphertext := secretbox.Seal(nil, plaintext, &nonce, key)
+
+	header := Header{
+		Driver:  d.Name(),
+		Version: 1,
+		KDF:     passphraseKDF,
+		Salt:    salt,
+		Nonce:   nonce[:],
+		Params: map[string]string{
+			"N": fmt.Sprintf("%d", passphraseN),
+			"r": fmt.Sprintf("%d", passphraseR),
+			"p": fmt.Sprintf("%d", passphraseP),
+		},
+	}
+
+	return ciphertext, header, nil
+}
+
+// Open implements Driver.
+func (d *PassphraseDriver) Open(ciphertext []byte, header Header) ([]byte, error) {
+	passphrase, err := d.readPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := d.deriveKey(passphrase, header.Salt, header.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], header.Nonce)
+
+	plaintext, ok := secretbox.Open(nil, ciphertext, &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt secret: authentication failed")
+	}
+
+	return plaintext, nil
+}
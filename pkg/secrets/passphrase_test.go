@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestPassphraseDriver(t *testing.T) *PassphraseDriver {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "master.passphrase")
+	if err := os.WriteFile(path, []byte("correct horse battery staple\n"), 0600); err != nil {
+		t.Fatalf("write passphrase: %s", err)
+	}
+	return NewPassphraseDriver(path)
+}
+
+func TestPassphraseDriverSealOpenRoundTrip(t *testing.T) {
+	d := newTestPassphraseDriver(t)
+
+	ciphertext, header, err := d.Seal([]byte("super-secret-value"))
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+
+	plaintext, err := d.Open(ciphertext, header)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+
+	if string(plaintext) != "super-secret-value" {
+		t.Fatalf("got %q, want %q", plaintext, "super-secret-value")
+	}
+}
+
+func TestPassphraseDriverOpenUsesHeaderParamsAfterCostChange(t *testing.T) {
+	d := newTestPassphraseDriver(t)
+
+	ciphertext, header, err := d.Seal([]byte("sealed-under-old-cost"))
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+
+	// Open must derive the key from the N/r/p stored in the header
+	// rather than the package defaults: with the sealing N it opens, and
+	// with a different N in the header it fails even though the package
+	// default still matches the sealing cost.
+	header.Params["N"] = "32768"
+	if _, err := d.Open(ciphertext, header); err != nil {
+		t.Fatalf("Open with unchanged header params: %s", err)
+	}
+
+	header.Params["N"] = "16384"
+	if _, err := d.Open(ciphertext, header); err == nil {
+		t.Fatalf("expected Open to fail when header params no longer match the sealing cost")
+	}
+}
+
+func TestPassphraseDriverOpenFallsBackWhenParamsMissing(t *testing.T) {
+	d := newTestPassphraseDriver(t)
+
+	ciphertext, header, err := d.Seal([]byte("legacy-secret"))
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+
+	header.Params = nil
+
+	plaintext, err := d.Open(ciphertext, header)
+	if err != nil {
+		t.Fatalf("Open without params: %s", err)
+	}
+	if string(plaintext) != "legacy-secret" {
+		t.Fatalf("got %q, want %q", plaintext, "legacy-secret")
+	}
+}
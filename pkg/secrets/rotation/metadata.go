@@ -0,0 +1,25 @@
+// Package rotation periodically walks deployed functions' secrets,
+// rotates any whose current version has exceeded the function's
+// configured TTL, and restarts the function so the new value is picked
+// up without a redeploy.
+package rotation
+
+import "time"
+
+// Metadata is the per-version bookkeeping record persisted alongside a
+// secret's ciphertext so the reconciler can tell how old the current
+// version is without touching it.
+type Metadata struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Version   int       `json:"version"`
+	Checksum  string    `json:"checksum"`
+}
+
+// Expired reports whether m's version is older than ttl, as of now. A
+// zero ttl means rotation is disabled for this secret.
+func (m Metadata) Expired(now time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return now.Sub(m.CreatedAt) >= ttl
+}
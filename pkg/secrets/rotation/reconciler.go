@@ -0,0 +1,161 @@
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// RotateLabel is the function label that opts a deployed function into
+// automatic secret rotation, e.g. `com.openfaas.secret.rotate: 24h`.
+const RotateLabel = "com.openfaas.secret.rotate"
+
+// Function describes a deployed function's rotation configuration, as
+// resolved by a NamespaceLister from the function's labels and secret
+// mounts.
+type Function struct {
+	Name    string
+	Secrets []string
+	TTL     time.Duration
+}
+
+// NamespaceLister enumerates the openfaas-labelled namespaces and, for
+// each, the functions deployed into it.
+type NamespaceLister interface {
+	Namespaces(ctx context.Context) ([]string, error)
+	Functions(ctx context.Context, namespace string) ([]Function, error)
+}
+
+// SecretRotator performs the actual rotation of a single secret: opening
+// its current version and sealing a fresh one under the same driver.
+type SecretRotator interface {
+	Rotate(namespace, name string, ttl time.Duration, now time.Time) (rotated bool, version int, err error)
+}
+
+// TaskRestarter restarts, or signals, the containerd task backing a
+// function so a newly rotated secret is picked up without a redeploy.
+type TaskRestarter interface {
+	RestartFunction(ctx context.Context, namespace, name string) error
+}
+
+// Status is the last known rotation outcome for a secret, as served by
+// GET /system/secrets/{name}/status.
+type Status struct {
+	Version     int       `json:"version"`
+	LastRotated time.Time `json:"lastRotated"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// Reconciler periodically walks every namespace's deployed functions,
+// rotates any secret whose current version is older than the function's
+// configured TTL, and restarts the function so it picks up the new
+// value.
+type Reconciler struct {
+	lister    NamespaceLister
+	rotator   SecretRotator
+	restarter TaskRestarter
+	interval  time.Duration
+
+	mux    sync.RWMutex
+	status map[string]Status
+}
+
+// NewReconciler builds a Reconciler that reconciles every interval.
+func NewReconciler(lister NamespaceLister, rotator SecretRotator, restarter TaskRestarter, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		lister:    lister,
+		rotator:   rotator,
+		restarter: restarter,
+		interval:  interval,
+		status:    map[string]Status{},
+	}
+}
+
+// Start runs the reconcile loop until ctx is cancelled.
+func (r *Reconciler) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reconcileOnce(ctx)
+		}
+	}
+}
+
+func (r *Reconciler) reconcileOnce(ctx context.Context) {
+	namespaces, err := r.lister.Namespaces(ctx)
+	if err != nil {
+		log.Printf("[rotation] unable to list namespaces: %s", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, namespace := range namespaces {
+		functions, err := r.lister.Functions(ctx, namespace)
+		if err != nil {
+			log.Printf("[rotation] unable to list functions in %s: %s", namespace, err)
+			continue
+		}
+
+		for _, fn := range functions {
+			if fn.TTL <= 0 {
+				continue
+			}
+
+			for _, secretName := range fn.Secrets {
+				r.reconcileSecret(ctx, namespace, fn, secretName, now)
+			}
+		}
+	}
+}
+
+func (r *Reconciler) reconcileSecret(ctx context.Context, namespace string, fn Function, secretName string, now time.Time) {
+	rotated, version, err := r.rotator.Rotate(namespace, secretName, fn.TTL, now)
+
+	key := statusKey(namespace, secretName)
+
+	if err != nil {
+		log.Printf("[rotation] failed to rotate %s in %s: %s", secretName, namespace, err)
+		r.recordStatus(key, Status{LastError: err.Error()})
+		return
+	}
+
+	if !rotated {
+		return
+	}
+
+	if err := r.restarter.RestartFunction(ctx, namespace, fn.Name); err != nil {
+		log.Printf("[rotation] rotated %s but failed to restart %s in %s: %s", secretName, fn.Name, namespace, err)
+		r.recordStatus(key, Status{Version: version, LastRotated: now, LastError: err.Error()})
+		return
+	}
+
+	log.Printf("[rotation] rotated %s to v%d and restarted %s in %s", secretName, version, fn.Name, namespace)
+	r.recordStatus(key, Status{Version: version, LastRotated: now})
+}
+
+func (r *Reconciler) recordStatus(key string, status Status) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.status[key] = status
+}
+
+// Status returns the last known rotation outcome for namespace/name.
+func (r *Reconciler) Status(namespace, name string) (Status, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	status, ok := r.status[statusKey(namespace, name)]
+	return status, ok
+}
+
+func statusKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
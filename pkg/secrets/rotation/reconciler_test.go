@@ -0,0 +1,122 @@
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type fakeLister struct {
+	namespaces []string
+	functions  map[string][]Function
+}
+
+func (l *fakeLister) Namespaces(ctx context.Context) ([]string, error) {
+	return l.namespaces, nil
+}
+
+func (l *fakeLister) Functions(ctx context.Context, namespace string) ([]Function, error) {
+	return l.functions[namespace], nil
+}
+
+type fakeRotator struct {
+	version     int
+	rotateErr   error
+	rotateCalls int
+}
+
+func (r *fakeRotator) Rotate(namespace, name string, ttl time.Duration, now time.Time) (bool, int, error) {
+	r.rotateCalls++
+	if r.rotateErr != nil {
+		return false, r.version, r.rotateErr
+	}
+	r.version++
+	return true, r.version, nil
+}
+
+type fakeRestarter struct {
+	restartCalls int
+	restartErr   error
+}
+
+func (r *fakeRestarter) RestartFunction(ctx context.Context, namespace, name string) error {
+	r.restartCalls++
+	return r.restartErr
+}
+
+func TestReconcilerBumpsVersionAndRestartsOnRotation(t *testing.T) {
+	lister := &fakeLister{
+		namespaces: []string{"openfaas-fn"},
+		functions: map[string][]Function{
+			"openfaas-fn": {{Name: "echo", Secrets: []string{"db-password"}, TTL: time.Hour}},
+		},
+	}
+	rotator := &fakeRotator{}
+	restarter := &fakeRestarter{}
+
+	r := NewReconciler(lister, rotator, restarter, time.Minute)
+	r.reconcileOnce(context.Background())
+
+	if rotator.rotateCalls != 1 {
+		t.Fatalf("got %d rotate calls, want 1", rotator.rotateCalls)
+	}
+	if restarter.restartCalls != 1 {
+		t.Fatalf("got %d restart calls, want 1", restarter.restartCalls)
+	}
+
+	status, ok := r.Status("openfaas-fn", "db-password")
+	if !ok {
+		t.Fatalf("expected a status to be recorded")
+	}
+	if status.Version != 1 {
+		t.Fatalf("got version %d, want 1", status.Version)
+	}
+	if status.LastError != "" {
+		t.Fatalf("got LastError %q, want empty", status.LastError)
+	}
+}
+
+func TestReconcilerSkipsFunctionsWithoutTTL(t *testing.T) {
+	lister := &fakeLister{
+		namespaces: []string{"openfaas-fn"},
+		functions: map[string][]Function{
+			"openfaas-fn": {{Name: "echo", Secrets: []string{"db-password"}, TTL: 0}},
+		},
+	}
+	rotator := &fakeRotator{}
+	restarter := &fakeRestarter{}
+
+	r := NewReconciler(lister, rotator, restarter, time.Minute)
+	r.reconcileOnce(context.Background())
+
+	if rotator.rotateCalls != 0 {
+		t.Fatalf("got %d rotate calls, want 0 for a function with no rotation TTL", rotator.rotateCalls)
+	}
+}
+
+func TestReconcilerRecordsRotateError(t *testing.T) {
+	lister := &fakeLister{
+		namespaces: []string{"openfaas-fn"},
+		functions: map[string][]Function{
+			"openfaas-fn": {{Name: "echo", Secrets: []string{"db-password"}, TTL: time.Hour}},
+		},
+	}
+	rotator := &fakeRotator{rotateErr: fmt.Errorf("unable to decrypt secret")}
+	restarter := &fakeRestarter{}
+
+	r := NewReconciler(lister, rotator, restarter, time.Minute)
+	r.reconcileOnce(context.Background())
+
+	if restarter.restartCalls != 0 {
+		t.Fatalf("got %d restart calls, want 0 after a rotate error", restarter.restartCalls)
+	}
+
+	status, ok := r.Status("openfaas-fn", "db-password")
+	if !ok {
+		t.Fatalf("expected a status to be recorded even on error")
+	}
+	if status.LastError == "" {
+		t.Fatalf("expected LastError to be set")
+	}
+}
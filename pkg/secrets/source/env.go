@@ -0,0 +1,48 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// EnvSource resolves "env://MY_VAR" references to the named environment
+// variable on the faasd host, restricted to the variable names in
+// Allowed so that a namespace-scoped secret writer cannot read
+// arbitrary process environment (credentials, tokens, etc. faasd itself
+// was started with) through a secret reference.
+type EnvSource struct {
+	// Allowed is the set of environment variable names this source will
+	// resolve. Any other name is rejected.
+	Allowed map[string]bool
+}
+
+// NewEnvSource builds an EnvSource that only resolves the variable
+// names in allowed.
+func NewEnvSource(allowed []string) *EnvSource {
+	set := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		set[name] = true
+	}
+
+	return &EnvSource{Allowed: set}
+}
+
+// Scheme implements Source.
+func (s *EnvSource) Scheme() string {
+	return "env"
+}
+
+// Resolve implements Source.
+func (s *EnvSource) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	if !s.Allowed[ref] {
+		return nil, fmt.Errorf("environment variable %q is not in the allowed list for env:// references", ref)
+	}
+
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %q is not set", ref)
+	}
+
+	return []byte(value), nil
+}
@@ -0,0 +1,55 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// FileSource resolves "file:///path/to/value" references by reading a
+// file from the faasd host's filesystem, rooted under Root so that a
+// namespace-scoped secret writer cannot reference files outside the
+// area the operator intended to expose (e.g. faasd's own passphrase
+// file or /etc/shadow).
+type FileSource struct {
+	// Root is the directory references are resolved relative to. A
+	// leading "/" and any ".." components are cleaned away before the
+	// join, so an absolute or ".." reference resolves inside Root rather
+	// than above it.
+	Root string
+}
+
+// NewFileSource builds a FileSource rooted at root. A relative root is
+// made absolute against the current working directory; root is not
+// checked for existence.
+func NewFileSource(root string) (*FileSource, error) {
+	if root == "" {
+		return nil, fmt.Errorf("file source root is required")
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve file source root %q: %s", root, err)
+	}
+
+	return &FileSource{Root: abs}, nil
+}
+
+// Scheme implements Source.
+func (s *FileSource) Scheme() string {
+	return "file"
+}
+
+// Resolve implements Source.
+func (s *FileSource) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	path := filepath.Join(s.Root, filepath.Clean("/"+ref))
+
+	rel, err := filepath.Rel(s.Root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("file reference %q escapes the configured root %q", ref, s.Root)
+	}
+
+	return ioutil.ReadFile(path)
+}
@@ -0,0 +1,78 @@
+// Package source lets faasd resolve a secret's value from somewhere
+// other than its own encrypted store at deploy time, e.g.
+// "vault://kv/data/myapp#password" or "env://MY_VAR". A Registry holds
+// one Source per URI scheme; each Source is responsible for deciding
+// what it is willing to resolve, since the registry itself has no way
+// to know which files or variables are safe for a given caller to read.
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Source resolves a secret reference with a particular URI scheme (for
+// example "vault", "env", or "file") to the plaintext material it names.
+type Source interface {
+	// Scheme is the URI scheme this Source handles, without "://".
+	Scheme() string
+
+	// Resolve fetches the value named by ref, the portion of the
+	// reference after "<scheme>://".
+	Resolve(ctx context.Context, ref string) ([]byte, error)
+}
+
+// Registry dispatches a secret reference to the Source registered for
+// its scheme. It starts out empty; callers register the Sources they
+// want available (file, env, vault, ...) at faasd startup via Register.
+type Registry struct {
+	sources map[string]Source
+}
+
+// NewRegistry builds an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{sources: map[string]Source{}}
+}
+
+// Register adds source to the registry, replacing any existing Source
+// registered for the same scheme.
+func (r *Registry) Register(source Source) {
+	r.sources[source.Scheme()] = source
+}
+
+// IsReference reports whether value looks like "<scheme>://<rest>" for a
+// scheme this registry has a Source for.
+func (r *Registry) IsReference(value string) bool {
+	scheme, _, ok := splitScheme(value)
+	if !ok {
+		return false
+	}
+
+	_, known := r.sources[scheme]
+	return known
+}
+
+// Resolve dispatches ref to the Source registered for its scheme.
+func (r *Registry) Resolve(ctx context.Context, ref string) ([]byte, error) {
+	scheme, rest, ok := splitScheme(ref)
+	if !ok {
+		return nil, fmt.Errorf("not a secret reference: %q", ref)
+	}
+
+	s, known := r.sources[scheme]
+	if !known {
+		return nil, fmt.Errorf("no secret source registered for scheme %q", scheme)
+	}
+
+	return s.Resolve(ctx, rest)
+}
+
+func splitScheme(value string) (scheme, rest string, ok bool) {
+	idx := strings.Index(value, "://")
+	if idx == -1 {
+		return "", "", false
+	}
+
+	return value[:idx], value[idx+len("://"):], true
+}